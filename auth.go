@@ -0,0 +1,38 @@
+package fcm
+
+import (
+	"context"
+	"io/ioutil"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// messagingScope is the OAuth2 scope required to call the FCM v1 HTTP API.
+const messagingScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// newTokenSource builds an oauth2.TokenSource for the FCM v1 API from a
+// service-account JSON key file. If credentialsFile is empty, Application
+// Default Credentials are used instead. The returned source caches and
+// refreshes tokens automatically.
+func newTokenSource(ctx context.Context, credentialsFile string) (oauth2.TokenSource, error) {
+	if credentialsFile == "" {
+		creds, err := google.FindDefaultCredentials(ctx, messagingScope)
+		if err != nil {
+			return nil, err
+		}
+		return creds.TokenSource, nil
+	}
+
+	data, err := ioutil.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, data, messagingScope)
+	if err != nil {
+		return nil, err
+	}
+
+	return creds.TokenSource, nil
+}
@@ -0,0 +1,61 @@
+package fcm
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffPolicy configures the exponential backoff SendWithRetryWithContext
+// applies between retry attempts.
+type BackoffPolicy struct {
+	// InitialInterval is the delay before the first retry.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the computed delay, before jitter is applied.
+	MaxInterval time.Duration
+
+	// Multiplier is applied to the interval after each attempt.
+	Multiplier float64
+
+	// RandomizationFactor jitters the computed interval by +/- this
+	// fraction, e.g. 0.5 spreads delays across [0.5x, 1.5x].
+	RandomizationFactor float64
+
+	// MaxElapsedTime bounds the total time spent retrying, measured from
+	// the first attempt. Zero means no bound.
+	MaxElapsedTime time.Duration
+
+	// MaxAttempts, if greater than zero, caps the number of attempts
+	// regardless of the retryAttempts argument passed to
+	// SendWithRetryWithContext.
+	MaxAttempts int
+}
+
+// DefaultBackoffPolicy is used by clients that don't call WithBackoff.
+var DefaultBackoffPolicy = BackoffPolicy{
+	InitialInterval:     500 * time.Millisecond,
+	MaxInterval:         60 * time.Second,
+	Multiplier:          2,
+	RandomizationFactor: 0.5,
+	MaxElapsedTime:      15 * time.Minute,
+}
+
+// next computes the delay before the given 0-indexed attempt, applying
+// RandomizationFactor jitter.
+func (b BackoffPolicy) next(attempt int) time.Duration {
+	interval := float64(b.InitialInterval) * math.Pow(b.Multiplier, float64(attempt))
+	if b.MaxInterval > 0 && interval > float64(b.MaxInterval) {
+		interval = float64(b.MaxInterval)
+	}
+
+	if b.RandomizationFactor > 0 {
+		delta := interval * b.RandomizationFactor
+		interval += delta*2*rand.Float64() - delta
+	}
+	if interval < 0 {
+		interval = 0
+	}
+
+	return time.Duration(interval)
+}
@@ -0,0 +1,82 @@
+package fcm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffPolicyNextCapsAtMaxInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  BackoffPolicy
+		attempt int
+	}{
+		{
+			name: "well under the cap",
+			policy: BackoffPolicy{
+				InitialInterval: 100 * time.Millisecond,
+				MaxInterval:     10 * time.Second,
+				Multiplier:      2,
+			},
+			attempt: 1,
+		},
+		{
+			name: "exceeds the cap",
+			policy: BackoffPolicy{
+				InitialInterval: time.Second,
+				MaxInterval:     2 * time.Second,
+				Multiplier:      10,
+			},
+			attempt: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.policy.next(tt.attempt)
+			if d > tt.policy.MaxInterval {
+				t.Errorf("next(%d) = %v, want <= MaxInterval %v", tt.attempt, d, tt.policy.MaxInterval)
+			}
+			if d < 0 {
+				t.Errorf("next(%d) = %v, want >= 0", tt.attempt, d)
+			}
+		})
+	}
+}
+
+func TestBackoffPolicyNextNoJitter(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval: 200 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+
+	if got, want := policy.next(0), 200*time.Millisecond; got != want {
+		t.Errorf("next(0) = %v, want %v", got, want)
+	}
+	if got, want := policy.next(1), 400*time.Millisecond; got != want {
+		t.Errorf("next(1) = %v, want %v", got, want)
+	}
+}
+
+func TestBackoffPolicyNextJitterRange(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     time.Second,
+		MaxInterval:         time.Minute,
+		Multiplier:          1,
+		RandomizationFactor: 0.5,
+	}
+
+	// Jitter is applied after the MaxInterval cap, so the bound is relative
+	// to the capped interval (here, the uncapped InitialInterval itself),
+	// not MaxInterval.
+	lo := time.Duration(float64(policy.InitialInterval) * 0.5)
+	hi := time.Duration(float64(policy.InitialInterval) * 1.5)
+
+	for i := 0; i < 200; i++ {
+		d := policy.next(0)
+		if d < lo || d > hi {
+			t.Fatalf("next(0) = %v, want in [%v, %v]", d, lo, hi)
+		}
+	}
+}
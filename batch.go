@@ -0,0 +1,278 @@
+package fcm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+const (
+	// batchEndpoint is the FCM batch endpoint that accepts a
+	// multipart/mixed request of up to MaxBatchMessages sub-requests.
+	batchEndpoint = "https://fcm.googleapis.com/batch"
+
+	// MaxBatchMessages is the maximum number of messages SendAll and
+	// SendMulticast will pack into a single /batch request.
+	MaxBatchMessages = 500
+)
+
+// ErrTooManyMessages occurs when SendAll is called with more than
+// MaxBatchMessages messages.
+var ErrTooManyMessages = errors.New("fcm: SendAll accepts at most 500 messages")
+
+// SendResponse is the outcome of sending a single message as part of a
+// batch request.
+type SendResponse struct {
+	MessageID string
+	Error     error
+}
+
+// BatchResponse aggregates the per-message results of SendAll and
+// SendMulticast.
+type BatchResponse struct {
+	SuccessCount int
+	FailureCount int
+	Responses    []SendResponse
+}
+
+// MulticastMessage is a Notification/Data payload addressed to many
+// registration tokens at once. SendMulticast fans it out into one Message
+// per token before packing them into a single batch request.
+type MulticastMessage struct {
+	Tokens       []string
+	Notification *Notification
+	Data         map[string]interface{}
+	Android      *AndroidConfig
+	APNS         *APNSConfig
+	Webpush      *WebpushConfig
+	FCMOptions   *FCMOptions
+}
+
+// messages expands m into one Message per token.
+func (m *MulticastMessage) messages() ([]*Message, error) {
+	if len(m.Tokens) == 0 {
+		return nil, ErrInvalidTarget
+	}
+
+	msgs := make([]*Message, len(m.Tokens))
+	for i, token := range m.Tokens {
+		msgs[i] = &Message{
+			To:           token,
+			Notification: m.Notification,
+			Data:         m.Data,
+			Android:      m.Android,
+			APNS:         m.APNS,
+			Webpush:      m.Webpush,
+			FCMOptions:   m.FCMOptions,
+		}
+	}
+	return msgs, nil
+}
+
+// SendMulticast sends the same notification/data payload to every token in
+// msg using a single /batch request.
+func (c *Client) SendMulticast(ctx context.Context, msg *MulticastMessage) (*BatchResponse, error) {
+	msgs, err := msg.messages()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SendAll(ctx, msgs)
+}
+
+// SendAll packs up to MaxBatchMessages messages into a single
+// multipart/mixed request to the FCM batch endpoint, as the firebase-admin
+// SDKs do. This is considerably cheaper than issuing one HTTP request per
+// message. SendAll requires a v1-capable Client; see NewClientV1.
+func (c *Client) SendAll(ctx context.Context, msgs []*Message) (*BatchResponse, error) {
+	if len(msgs) == 0 {
+		return &BatchResponse{}, nil
+	}
+	if len(msgs) > MaxBatchMessages {
+		return nil, ErrTooManyMessages
+	}
+	if c.tokenSource == nil {
+		return nil, errors.New("fcm: SendAll requires a v1-capable client; use NewClientV1")
+	}
+
+	for _, msg := range msgs {
+		if err := msg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	tok, err := c.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	body, boundary, err := c.buildBatchBody(msgs, tok.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", batchEndpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+boundary)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, connectionError(err.Error())
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseV1Error(resp.StatusCode, respBody)
+	}
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBatchResponse(respBody, params["boundary"])
+}
+
+// buildBatchBody serializes msgs into the multipart/mixed body expected by
+// the FCM batch endpoint: one part per message, each part holding a raw
+// HTTP/1.1 POST .../messages:send sub-request.
+func (c *Client) buildBatchBody(msgs []*Message, accessToken string) (io.Reader, string, error) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	for i, msg := range msgs {
+		v1Msg, err := toV1Message(msg)
+		if err != nil {
+			return nil, "", err
+		}
+
+		payload, err := json.Marshal(v1Envelope{Message: v1Msg})
+		if err != nil {
+			return nil, "", err
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Type", "application/http")
+		header.Set("Content-Transfer-Encoding", "binary")
+		header.Set("Content-ID", strconv.Itoa(i+1))
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+
+		sub := &bytes.Buffer{}
+		fmt.Fprintf(sub, "POST /v1/projects/%s/messages:send HTTP/1.1\r\n", c.projectID)
+		fmt.Fprintf(sub, "Content-Type: application/json\r\n")
+		fmt.Fprintf(sub, "Authorization: Bearer %s\r\n", accessToken)
+		sub.WriteString("\r\n")
+		sub.Write(payload)
+
+		if _, err := part.Write(sub.Bytes()); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf, w.Boundary(), nil
+}
+
+// parseBatchResponse splits a /batch multipart/mixed response body on
+// boundary and parses each part's embedded HTTP sub-response.
+func parseBatchResponse(body []byte, boundary string) (*BatchResponse, error) {
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	batch := &BatchResponse{}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		sr, err := parseSubResponse(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		batch.Responses = append(batch.Responses, *sr)
+		if sr.Error != nil {
+			batch.FailureCount++
+		} else {
+			batch.SuccessCount++
+		}
+	}
+
+	return batch, nil
+}
+
+// parseSubResponse parses the raw HTTP/1.1 response embedded in a single
+// batch part into a SendResponse.
+func parseSubResponse(raw []byte) (*SendResponse, error) {
+	tp := textproto.NewReader(bufio.NewReader(bytes.NewReader(raw)))
+
+	statusLine, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.SplitN(statusLine, " ", 3)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("fcm: malformed batch sub-response status line %q", statusLine)
+	}
+	statusCode, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("fcm: malformed batch sub-response status line %q", statusLine)
+	}
+
+	if _, err := tp.ReadMIMEHeader(); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(tp.R)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return &SendResponse{Error: parseV1Error(statusCode, bodyBytes)}, nil
+	}
+
+	var sent struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(bodyBytes, &sent); err != nil {
+		return nil, err
+	}
+
+	return &SendResponse{MessageID: sent.Name}, nil
+}
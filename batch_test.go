@@ -0,0 +1,120 @@
+package fcm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func TestBuildBatchBody(t *testing.T) {
+	c := &Client{projectID: "test-project"}
+
+	msgs := []*Message{
+		{To: "token-1", Notification: &Notification{Title: "hi"}},
+		{To: "token-2"},
+	}
+
+	body, boundary, err := c.buildBatchBody(msgs, "fake-token")
+	if err != nil {
+		t.Fatalf("buildBatchBody: %v", err)
+	}
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	mr := multipart.NewReader(bytes.NewReader(raw), boundary)
+	var parts []string
+	for i := 0; ; i++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+
+		if got := part.Header.Get("Content-Type"); got != "application/http" {
+			t.Errorf("part %d Content-Type = %q, want application/http", i, got)
+		}
+		if got := part.Header.Get("Content-ID"); got != fmt.Sprintf("%d", i+1) {
+			t.Errorf("part %d Content-ID = %q, want %d", i, got, i+1)
+		}
+
+		sub, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		parts = append(parts, string(sub))
+	}
+
+	if len(parts) != len(msgs) {
+		t.Fatalf("got %d parts, want %d", len(parts), len(msgs))
+	}
+
+	const wantLine = "POST /v1/projects/test-project/messages:send HTTP/1.1"
+	for i, p := range parts {
+		if !strings.HasPrefix(p, wantLine) {
+			t.Errorf("part %d does not start with %q:\n%s", i, wantLine, p)
+		}
+		if !strings.Contains(p, "Authorization: Bearer fake-token") {
+			t.Errorf("part %d missing bearer token:\n%s", i, p)
+		}
+
+		sep := strings.Index(p, "\r\n\r\n")
+		if sep == -1 {
+			t.Fatalf("part %d has no header/body separator", i)
+		}
+
+		var envelope v1Envelope
+		if err := json.Unmarshal([]byte(p[sep+4:]), &envelope); err != nil {
+			t.Fatalf("part %d: unmarshal payload: %v", i, err)
+		}
+		if envelope.Message.Token != msgs[i].To {
+			t.Errorf("part %d token = %q, want %q", i, envelope.Message.Token, msgs[i].To)
+		}
+	}
+}
+
+func TestParseBatchResponse(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	ok, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok.Write([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"name\":\"projects/p/messages/1\"}"))
+
+	bad, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad.Write([]byte("HTTP/1.1 404 Not Found\r\nContent-Type: application/json\r\n\r\n" +
+		`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := parseBatchResponse(buf.Bytes(), w.Boundary())
+	if err != nil {
+		t.Fatalf("parseBatchResponse: %v", err)
+	}
+
+	if resp.SuccessCount != 1 || resp.FailureCount != 1 {
+		t.Fatalf("got success=%d failure=%d, want 1/1", resp.SuccessCount, resp.FailureCount)
+	}
+	if resp.Responses[0].MessageID != "projects/p/messages/1" {
+		t.Errorf("Responses[0].MessageID = %q", resp.Responses[0].MessageID)
+	}
+	if resp.Responses[1].Error != ErrUnregistered {
+		t.Errorf("Responses[1].Error = %v, want ErrUnregistered", resp.Responses[1].Error)
+	}
+}
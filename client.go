@@ -8,8 +8,11 @@ import (
 	firebase "firebase.google.com/go"
 	"firebase.google.com/go/messaging"
 	"fmt"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -25,6 +28,10 @@ var (
 	// ErrInvalidAPIKey occurs if API key is not set.
 	ErrInvalidAPIKey          = errors.New("client API Key is invalid")
 	ErrInvalidCredentialsPath = errors.New("credentials path is invalid")
+
+	// ErrInvalidProjectID occurs if NewClientV1 is called without a Firebase
+	// project ID.
+	ErrInvalidProjectID = errors.New("client project ID is invalid")
 )
 
 // Client abstracts the interaction between the application server and the
@@ -42,6 +49,14 @@ type Client struct {
 	timeout   time.Duration
 	app       *firebase.App
 	msgClient *messaging.Client
+
+	// projectID, tokenSource and legacy configure the FCM v1 HTTP API path.
+	// See NewClientV1 and WithLegacyAPI.
+	projectID       string
+	credentialsFile string
+	tokenSource     oauth2.TokenSource
+	legacy          bool
+	backoff         BackoffPolicy
 }
 
 // NewClient creates new Firebase Cloud Messaging Client based on API key and
@@ -56,6 +71,7 @@ func NewClient(apiKey string, opts ...Option) (*Client, error) {
 		client:   &http.Client{},
 		timeout:  DefaultTimeout,
 		app:      nil,
+		backoff:  DefaultBackoffPolicy,
 	}
 	for _, o := range opts {
 		if err := o(c); err != nil {
@@ -85,6 +101,7 @@ func NewClientWithCredentials(path string, opts ...Option) (*Client, error) {
 		timeout:   DefaultTimeout,
 		app:       app,
 		msgClient: nil,
+		backoff:   DefaultBackoffPolicy,
 	}
 
 	for _, o := range opts {
@@ -96,6 +113,42 @@ func NewClientWithCredentials(path string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
+// NewClientV1 creates a new Firebase Cloud Messaging Client that sends
+// through the FCM HTTP v1 API (https://fcm.googleapis.com/v1/projects/{projectID}/messages:send),
+// authenticating with an OAuth2 token obtained from service-account
+// credentials. Use WithCredentialsFile to point at a service-account JSON
+// key; Application Default Credentials are used otherwise. Pass
+// WithLegacyAPI to fall back to the deprecated key-based endpoint instead.
+func NewClientV1(projectID string, opts ...Option) (*Client, error) {
+	if projectID == "" {
+		return nil, ErrInvalidProjectID
+	}
+
+	c := &Client{
+		projectID: projectID,
+		endpoint:  DefaultEndpoint,
+		client:    &http.Client{},
+		timeout:   DefaultTimeout,
+		backoff:   DefaultBackoffPolicy,
+	}
+
+	for _, o := range opts {
+		if err := o(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if !c.legacy {
+		ts, err := newTokenSource(context.Background(), c.credentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		c.tokenSource = ts
+	}
+
+	return c, nil
+}
+
 // SendWithContext sends a message to the FCM server without retrying in case of service
 // unavailability. A non-nil error is returned if a non-recoverable error
 // occurs (i.e. if the response status is not "200 OK").
@@ -108,15 +161,19 @@ func (c *Client) SendWithContext(ctx context.Context, msg *Message) (*Response,
 
 	if c.app != nil {
 		return c.sendByApp(ctx, msg)
-	} else {
-		// marshal message
-		data, err := json.Marshal(msg)
-		if err != nil {
-			return nil, err
-		}
+	}
 
-		return c.send(ctx, data)
+	if c.tokenSource != nil && !c.legacy {
+		return c.sendV1(ctx, msg)
+	}
+
+	// marshal message
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
 	}
+
+	return c.send(ctx, data)
 }
 
 // Send sends a message to the FCM server without retrying in case of service
@@ -143,20 +200,18 @@ func (c *Client) SendWithRetryWithContext(ctx context.Context, msg *Message, ret
 	if err := msg.Validate(); err != nil {
 		return nil, err
 	}
-	// marshal message
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
-	}
 
 	resp := new(Response)
-	err = retry(func() error {
-		ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	err := retry(ctx, c.backoff, retryAttempts, func() error {
+		reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
 		defer cancel()
-		var er error
-		resp, er = c.send(ctx, data)
+
+		r, er := c.SendWithContext(reqCtx, msg)
+		if er == nil {
+			resp = r
+		}
 		return er
-	}, retryAttempts)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -202,6 +257,65 @@ func (c *Client) send(ctx context.Context, data []byte) (*Response, error) {
 	return response, nil
 }
 
+// sendV1 sends a message through the FCM HTTP v1 API, authenticating with
+// a bearer token obtained from c.tokenSource.
+func (c *Client) sendV1(ctx context.Context, msg *Message) (*Response, error) {
+	v1Msg, err := toV1Message(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(v1Envelope{Message: v1Msg})
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := c.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf(v1Endpoint, c.projectID), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, connectionError(err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := parseV1Error(resp.StatusCode, body)
+		if delay := parseRetryAfter(resp.Header.Get("Retry-After")); delay > 0 {
+			return nil, &retryAfterError{err: apiErr, delay: delay}
+		}
+		return nil, apiErr
+	}
+
+	var sent struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Success: 1,
+		Results: []Result{{MessageID: sent.Name}},
+	}, nil
+}
+
 // send by app
 func (c *Client) sendByApp(ctx context.Context, msg *Message) (*Response, error) {
 	if c.msgClient == nil {
@@ -216,10 +330,53 @@ func (c *Client) sendByApp(ctx context.Context, msg *Message) (*Response, error)
 	for k, v := range msg.Data {
 		data[k] = v.(string)
 	}
-	if len(msg.RegistrationIDs) > 0 && len(msg.RegistrationIDs) < 2 {
+	if msg.Topic != "" || msg.Condition != "" {
+		apnsCfg, err := toAdminAPNSConfig(msg.APNS)
+		if err != nil {
+			return nil, err
+		}
+		fcmMsg := &messaging.Message{
+			Topic:      strings.TrimPrefix(msg.Topic, "/topics/"),
+			Condition:  msg.Condition,
+			Data:       data,
+			Android:    toAdminAndroidConfig(msg.Android),
+			APNS:       apnsCfg,
+			Webpush:    toAdminWebpushConfig(msg.Webpush),
+			FCMOptions: toAdminFCMOptions(msg.FCMOptions),
+		}
+
+		if msg.Notification != nil {
+			fcmMsg.Notification = &messaging.Notification{
+				Title: msg.Notification.Title,
+				Body:  msg.Notification.Body,
+			}
+		}
+
+		r, err := c.msgClient.Send(ctx, fcmMsg)
+		res := &Response{}
+		if err != nil {
+			res.Success = 0
+			res.Failure = 1
+		} else {
+			res.Success = 1
+			res.Failure = 0
+		}
+		res.Error = err
+		res.ErrorResponseCode = r
+
+		return res, err
+	} else if len(msg.RegistrationIDs) > 0 && len(msg.RegistrationIDs) < 2 {
+		apnsCfg, err := toAdminAPNSConfig(msg.APNS)
+		if err != nil {
+			return nil, err
+		}
 		fcmMsg := &messaging.Message{
-			Token: msg.RegistrationIDs[0],
-			Data:  data,
+			Token:      msg.RegistrationIDs[0],
+			Data:       data,
+			Android:    toAdminAndroidConfig(msg.Android),
+			APNS:       apnsCfg,
+			Webpush:    toAdminWebpushConfig(msg.Webpush),
+			FCMOptions: toAdminFCMOptions(msg.FCMOptions),
 		}
 
 		if msg.Notification != nil {
@@ -243,10 +400,55 @@ func (c *Client) sendByApp(ctx context.Context, msg *Message) (*Response, error)
 		res.ErrorResponseCode = r
 
 		return res, err
+	} else if msg.FCMOptions != nil {
+		// messaging.MulticastMessage has no FCMOptions field in the
+		// firebase-admin SDK, so fan out per token instead: each
+		// messaging.Message does carry it.
+		apnsCfg, err := toAdminAPNSConfig(msg.APNS)
+		if err != nil {
+			return nil, err
+		}
+
+		res := &Response{}
+		for _, token := range msg.RegistrationIDs {
+			fcmMsg := &messaging.Message{
+				Token:      token,
+				Data:       data,
+				Android:    toAdminAndroidConfig(msg.Android),
+				APNS:       apnsCfg,
+				Webpush:    toAdminWebpushConfig(msg.Webpush),
+				FCMOptions: toAdminFCMOptions(msg.FCMOptions),
+			}
+
+			if msg.Notification != nil {
+				fcmMsg.Notification = &messaging.Notification{
+					Title: msg.Notification.Title,
+					Body:  msg.Notification.Body,
+				}
+			}
+
+			id, err := c.msgClient.Send(ctx, fcmMsg)
+			if err != nil {
+				res.Failure++
+				res.Results = append(res.Results, Result{Error: err, ErrorResponseCode: err.Error()})
+				continue
+			}
+			res.Success++
+			res.Results = append(res.Results, Result{MessageID: id})
+		}
+
+		return res, nil
 	} else {
+		apnsCfg, err := toAdminAPNSConfig(msg.APNS)
+		if err != nil {
+			return nil, err
+		}
 		fcmMsg := &messaging.MulticastMessage{
-			Tokens: msg.RegistrationIDs,
-			Data:   data,
+			Tokens:  msg.RegistrationIDs,
+			Data:    data,
+			Android: toAdminAndroidConfig(msg.Android),
+			APNS:    apnsCfg,
+			Webpush: toAdminWebpushConfig(msg.Webpush),
 		}
 
 		if msg.Notification != nil {
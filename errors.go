@@ -0,0 +1,16 @@
+package fcm
+
+// connectionError indicates a transport-level failure (DNS, TLS, a dropped
+// connection, ...) while talking to the FCM server.
+type connectionError string
+
+func (e connectionError) Error() string {
+	return string(e)
+}
+
+// serverError indicates the FCM server responded with a 5xx status.
+type serverError string
+
+func (e serverError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,138 @@
+package fcm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Typed errors returned by the FCM v1 API, derived from error.status and
+// error.details[].errorCode in the response body. See
+// https://firebase.google.com/docs/reference/fcm/rest/v1/ErrorCode.
+var (
+	// ErrInvalidArgument occurs when the request body is malformed, e.g. an
+	// invalid registration token or package name.
+	ErrInvalidArgument = fmt.Errorf("fcm: invalid argument")
+
+	// ErrUnregistered occurs when the target app instance is unregistered;
+	// the registration token is no longer valid and should be removed from
+	// the caller's database.
+	ErrUnregistered = fmt.Errorf("fcm: app instance unregistered")
+
+	// ErrSenderIDMismatch occurs when the registration token belongs to a
+	// different sender/project than the one making the request.
+	ErrSenderIDMismatch = fmt.Errorf("fcm: sender ID mismatch")
+
+	// ErrQuotaExceeded occurs when the sending quota for the project, device
+	// or message rate has been exceeded.
+	ErrQuotaExceeded = fmt.Errorf("fcm: quota exceeded")
+
+	// ErrUnavailable occurs when the FCM server is overloaded or down.
+	ErrUnavailable = fmt.Errorf("fcm: server unavailable")
+
+	// ErrInternal occurs on an unknown internal FCM server error.
+	ErrInternal = fmt.Errorf("fcm: internal server error")
+
+	// ErrThirdPartyAuth occurs when the APNs certificate/key or web push
+	// auth key is invalid or missing.
+	ErrThirdPartyAuth = fmt.Errorf("fcm: third-party auth error")
+)
+
+// v1ErrorBody is the JSON error envelope returned by the FCM v1 API.
+type v1ErrorBody struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			Type      string `json:"@type"`
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// parseV1Error maps a non-2xx FCM v1 response body onto one of the typed
+// errors above. When the body can't be parsed as a v1 error envelope, a 5xx
+// status maps to ErrInternal and any other status to a generic error; an
+// unrecognized status in a parsed envelope also yields a generic error.
+func parseV1Error(statusCode int, body []byte) error {
+	var e v1ErrorBody
+	if err := json.Unmarshal(body, &e); err != nil || e.Error.Status == "" {
+		if statusCode >= http.StatusInternalServerError {
+			return ErrInternal
+		}
+		return fmt.Errorf("%d error: %s", statusCode, body)
+	}
+
+	for _, d := range e.Error.Details {
+		if err, ok := fcmErrorCodes[d.ErrorCode]; ok {
+			return err
+		}
+	}
+
+	if err, ok := fcmStatusCodes[e.Error.Status]; ok {
+		return err
+	}
+
+	return fmt.Errorf("fcm: %s: %s", e.Error.Status, e.Error.Message)
+}
+
+var fcmErrorCodes = map[string]error{
+	"UNREGISTERED":           ErrUnregistered,
+	"INVALID_ARGUMENT":       ErrInvalidArgument,
+	"SENDER_ID_MISMATCH":     ErrSenderIDMismatch,
+	"QUOTA_EXCEEDED":         ErrQuotaExceeded,
+	"UNAVAILABLE":            ErrUnavailable,
+	"INTERNAL":               ErrInternal,
+	"THIRD_PARTY_AUTH_ERROR": ErrThirdPartyAuth,
+}
+
+var fcmStatusCodes = map[string]error{
+	"INVALID_ARGUMENT":   ErrInvalidArgument,
+	"NOT_FOUND":          ErrUnregistered,
+	"PERMISSION_DENIED":  ErrSenderIDMismatch,
+	"RESOURCE_EXHAUSTED": ErrQuotaExceeded,
+	"UNAVAILABLE":        ErrUnavailable,
+	"INTERNAL":           ErrInternal,
+}
+
+// retryAfterError wraps an error with the delay the server asked us to wait
+// before retrying, taken from a Retry-After response header.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// parseRetryAfter parses a Retry-After header value, given either as a
+// number of seconds or an HTTP-date. It returns 0 if the header is absent,
+// malformed, or already in the past.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
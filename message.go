@@ -0,0 +1,128 @@
+package fcm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// MaxRegistrationIDs is the maximum number of registration tokens FCM
+// accepts in a single legacy multicast message.
+const MaxRegistrationIDs = 1000
+
+var (
+	// ErrInvalidTarget occurs if a message has none of To, RegistrationIDs,
+	// Topic or Condition set.
+	ErrInvalidTarget = errors.New("message must have one of To, RegistrationIDs, Topic or Condition set")
+
+	// ErrTooManyRegIDs occurs if a message has more registration IDs than
+	// MaxRegistrationIDs.
+	ErrTooManyRegIDs = errors.New("message has more registration IDs than allowed")
+
+	// ErrInvalidTTL occurs if AndroidConfig.TTL is negative.
+	ErrInvalidTTL = errors.New("android TTL must be non-negative")
+
+	// ErrInvalidAPNSPriority occurs if the "apns-priority" header is set to
+	// anything other than "5" or "10".
+	ErrInvalidAPNSPriority = errors.New(`apns-priority header must be "5" or "10"`)
+
+	// ErrMultipleTargets occurs if a message sets more than one of To,
+	// RegistrationIDs, Topic and Condition, which are mutually exclusive.
+	ErrMultipleTargets = errors.New("message must have exactly one of To, RegistrationIDs, Topic or Condition set")
+)
+
+// Notification carries the user-visible parts of a push notification.
+type Notification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// Message represents an FCM request message.
+type Message struct {
+	To              string                 `json:"to,omitempty"`
+	RegistrationIDs []string               `json:"registration_ids,omitempty"`
+	Notification    *Notification          `json:"notification,omitempty"`
+	Data            map[string]interface{} `json:"data,omitempty"`
+
+	// Topic and Condition are alternative targets to To/RegistrationIDs.
+	// Topic is the bare topic name (an optional "/topics/" prefix is
+	// stripped before sending) and Condition is a boolean expression over
+	// topics, e.g. "'TopicA' in topics && 'TopicB' in topics". Both are
+	// sent on the v1, legacy and firebase-admin send paths.
+	Topic     string `json:"-"`
+	Condition string `json:"-"`
+
+	// Android, APNS and Webpush carry platform-specific delivery options.
+	// FCMOptions carries options that apply regardless of platform.
+	Android    *AndroidConfig `json:"android,omitempty"`
+	APNS       *APNSConfig    `json:"apns,omitempty"`
+	Webpush    *WebpushConfig `json:"webpush,omitempty"`
+	FCMOptions *FCMOptions    `json:"fcm_options,omitempty"`
+}
+
+// Validate returns an error if the message is missing a target, combines
+// fields that are mutually exclusive, or sets a platform config field to an
+// invalid value.
+func (m *Message) Validate() error {
+	targets := 0
+	if m.To != "" {
+		targets++
+	}
+	if len(m.RegistrationIDs) > 0 {
+		targets++
+	}
+	if m.Topic != "" {
+		targets++
+	}
+	if m.Condition != "" {
+		targets++
+	}
+	switch {
+	case targets == 0:
+		return ErrInvalidTarget
+	case targets > 1:
+		return ErrMultipleTargets
+	}
+
+	if len(m.RegistrationIDs) > MaxRegistrationIDs {
+		return ErrTooManyRegIDs
+	}
+
+	if m.Android != nil && m.Android.TTL < 0 {
+		return ErrInvalidTTL
+	}
+
+	if m.APNS != nil {
+		if p, ok := m.APNS.Headers["apns-priority"]; ok && p != "5" && p != "10" {
+			return ErrInvalidAPNSPriority
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON serializes Message into the legacy FCM wire format used by
+// Client.send. Topic and Condition are tagged json:"-" above because the v1
+// send path (toV1Message) serializes them onto v1Message separately; here,
+// for the legacy endpoint, a topic is addressed via "to":"/topics/<topic>"
+// and a condition via its own "condition" field.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	type alias Message
+	aux := struct {
+		To        string `json:"to,omitempty"`
+		Condition string `json:"condition,omitempty"`
+		*alias
+	}{
+		To:    m.To,
+		alias: (*alias)(m),
+	}
+
+	switch {
+	case m.Topic != "":
+		aux.To = "/topics/" + strings.TrimPrefix(m.Topic, "/topics/")
+	case m.Condition != "":
+		aux.Condition = m.Condition
+	}
+
+	return json.Marshal(aux)
+}
@@ -0,0 +1,76 @@
+package fcm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// v1Endpoint is the FCM HTTP v1 endpoint template; the project ID is
+// substituted in.
+const v1Endpoint = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+
+// ErrTooManyTargets occurs when a Message with more than one registration
+// ID is sent through the v1 messages:send endpoint, which only accepts a
+// single target. Use SendMulticast or SendAll for multiple tokens.
+var ErrTooManyTargets = errors.New("fcm: messages:send accepts a single target; use SendMulticast for multiple registration IDs")
+
+// v1Envelope is the outer JSON object expected by the v1 messages:send API.
+type v1Envelope struct {
+	Message *v1Message `json:"message"`
+}
+
+// v1Message is the v1 API representation of Message.
+type v1Message struct {
+	Token        string            `json:"token,omitempty"`
+	Topic        string            `json:"topic,omitempty"`
+	Condition    string            `json:"condition,omitempty"`
+	Notification *Notification     `json:"notification,omitempty"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      *AndroidConfig    `json:"android,omitempty"`
+	APNS         *APNSConfig       `json:"apns,omitempty"`
+	Webpush      *WebpushConfig    `json:"webpush,omitempty"`
+	FCMOptions   *FCMOptions       `json:"fcm_options,omitempty"`
+}
+
+// toV1Message translates Message into its v1 API representation.
+func toV1Message(msg *Message) (*v1Message, error) {
+	v1Msg := &v1Message{
+		Notification: msg.Notification,
+		Android:      msg.Android,
+		APNS:         msg.APNS,
+		Webpush:      msg.Webpush,
+		FCMOptions:   msg.FCMOptions,
+	}
+
+	switch {
+	case msg.Topic != "":
+		v1Msg.Topic = strings.TrimPrefix(msg.Topic, "/topics/")
+	case msg.Condition != "":
+		v1Msg.Condition = msg.Condition
+	case msg.To != "":
+		v1Msg.Token = msg.To
+	case len(msg.RegistrationIDs) == 1:
+		v1Msg.Token = msg.RegistrationIDs[0]
+	case len(msg.RegistrationIDs) > 1:
+		return nil, ErrTooManyTargets
+	}
+
+	if len(msg.Data) > 0 {
+		data := make(map[string]string, len(msg.Data))
+		for k, v := range msg.Data {
+			if s, ok := v.(string); ok {
+				data[k] = s
+				continue
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			data[k] = string(b)
+		}
+		v1Msg.Data = data
+	}
+
+	return v1Msg, nil
+}
@@ -0,0 +1,74 @@
+package fcm
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client during construction.
+type Option func(*Client) error
+
+// WithEndpoint overrides DefaultEndpoint, the legacy fcm/send endpoint.
+// It has no effect on v1 sends, SendAll/SendMulticast or topic
+// management, which use fixed Google endpoints.
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) error {
+		c.endpoint = endpoint
+		return nil
+	}
+}
+
+// WithHTTPClient sets a custom http.Client used to perform requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) error {
+		c.client = client
+		return nil
+	}
+}
+
+// WithTimeout overrides DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithAPIKey sets the legacy server API key used by the deprecated
+// fcm/send endpoint. Only needed together with WithLegacyAPI.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) error {
+		c.apiKey = apiKey
+		return nil
+	}
+}
+
+// WithCredentialsFile points NewClientV1 at a service-account JSON key file
+// used to obtain OAuth2 tokens for the FCM v1 API. When unset, Application
+// Default Credentials are used instead.
+func WithCredentialsFile(path string) Option {
+	return func(c *Client) error {
+		c.credentialsFile = path
+		return nil
+	}
+}
+
+// WithBackoff overrides DefaultBackoffPolicy for a Client's
+// SendWithRetryWithContext calls.
+func WithBackoff(policy BackoffPolicy) Option {
+	return func(c *Client) error {
+		c.backoff = policy
+		return nil
+	}
+}
+
+// WithLegacyAPI routes Send, SendWithContext and SendWithRetryWithContext
+// through the deprecated https://fcm.googleapis.com/fcm/send endpoint
+// instead of the FCM v1 API. Only use this for applications that still
+// authenticate with a legacy server API key (see WithAPIKey).
+func WithLegacyAPI() Option {
+	return func(c *Client) error {
+		c.legacy = true
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package fcm
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// FCMOptions carries options that apply across all platforms for a single
+// message.
+type FCMOptions struct {
+	// AnalyticsLabel is forwarded to the Firebase console's message
+	// analytics for this send.
+	AnalyticsLabel string `json:"analytics_label,omitempty"`
+}
+
+// AndroidNotification holds Android-specific display fields for a
+// notification, layered on top of Message.Notification.
+type AndroidNotification struct {
+	Sound                string `json:"sound,omitempty"`
+	ClickAction          string `json:"click_action,omitempty"`
+	Icon                 string `json:"icon,omitempty"`
+	Color                string `json:"color,omitempty"`
+	ChannelID            string `json:"channel_id,omitempty"`
+	NotificationPriority string `json:"notification_priority,omitempty"`
+}
+
+// AndroidConfig carries Android-specific delivery options for a message.
+type AndroidConfig struct {
+	// Priority is either "normal" or "high".
+	Priority string `json:"priority,omitempty"`
+
+	// TTL is how long FCM should retry delivery. It is marshaled as a
+	// "<seconds>s" duration string, per the v1 API.
+	TTL time.Duration `json:"-"`
+
+	CollapseKey           string               `json:"collapse_key,omitempty"`
+	RestrictedPackageName string               `json:"restricted_package_name,omitempty"`
+	Data                  map[string]string    `json:"data,omitempty"`
+	Notification          *AndroidNotification `json:"notification,omitempty"`
+}
+
+// MarshalJSON serializes AndroidConfig into the v1 API wire format, encoding
+// TTL as a "<seconds>s" duration string.
+func (a *AndroidConfig) MarshalJSON() ([]byte, error) {
+	type alias AndroidConfig
+	aux := struct {
+		TTL string `json:"ttl,omitempty"`
+		*alias
+	}{
+		alias: (*alias)(a),
+	}
+	if a.TTL > 0 {
+		aux.TTL = fmt.Sprintf("%.0fs", a.TTL.Seconds())
+	}
+	return json.Marshal(aux)
+}
+
+// Aps is the payload of the "aps" dictionary Apple expects in a push
+// notification. Alert may be a plain string or a richer object, so it is
+// left untyped.
+type Aps struct {
+	Alert            interface{} `json:"alert,omitempty"`
+	Badge            *int        `json:"badge,omitempty"`
+	Sound            string      `json:"sound,omitempty"`
+	ContentAvailable bool        `json:"content-available,omitempty"`
+	MutableContent   bool        `json:"mutable-content,omitempty"`
+	Category         string      `json:"category,omitempty"`
+	ThreadID         string      `json:"thread-id,omitempty"`
+}
+
+// APNSPayload is the JSON payload of an APNS message. CustomData is merged
+// alongside "aps" as top-level keys, matching Apple's flat payload format.
+type APNSPayload struct {
+	Aps        *Aps                   `json:"-"`
+	CustomData map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON merges Aps and CustomData into a single flat JSON object.
+func (p *APNSPayload) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.CustomData)+1)
+	for k, v := range p.CustomData {
+		m[k] = v
+	}
+	m["aps"] = p.Aps
+	return json.Marshal(m)
+}
+
+// APNSConfig carries APNS-specific delivery options and payload for a
+// message.
+type APNSConfig struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload *APNSPayload      `json:"payload,omitempty"`
+}
+
+// WebpushFCMOptions carries webpush-specific FCM options for a message.
+type WebpushFCMOptions struct {
+	Link string `json:"link,omitempty"`
+}
+
+// WebpushConfig carries webpush-specific delivery options for a message.
+type WebpushConfig struct {
+	Headers      map[string]string      `json:"headers,omitempty"`
+	Data         map[string]string      `json:"data,omitempty"`
+	Notification map[string]interface{} `json:"notification,omitempty"`
+	FCMOptions   *WebpushFCMOptions     `json:"fcm_options,omitempty"`
+}
@@ -0,0 +1,135 @@
+package fcm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"firebase.google.com/go/messaging"
+)
+
+// toAdminAndroidConfig translates our AndroidConfig into the firebase-admin
+// SDK's equivalent, used by the sendByApp path.
+func toAdminAndroidConfig(a *AndroidConfig) *messaging.AndroidConfig {
+	if a == nil {
+		return nil
+	}
+
+	cfg := &messaging.AndroidConfig{
+		CollapseKey:           a.CollapseKey,
+		Priority:              a.Priority,
+		RestrictedPackageName: a.RestrictedPackageName,
+		Data:                  a.Data,
+	}
+	if a.TTL > 0 {
+		ttl := a.TTL
+		cfg.TTL = &ttl
+	}
+	if a.Notification != nil {
+		cfg.Notification = &messaging.AndroidNotification{
+			Sound:       a.Notification.Sound,
+			ClickAction: a.Notification.ClickAction,
+			Icon:        a.Notification.Icon,
+			Color:       a.Notification.Color,
+			ChannelID:   a.Notification.ChannelID,
+			Priority:    androidNotificationPriority(a.Notification.NotificationPriority),
+		}
+	}
+	return cfg
+}
+
+// androidNotificationPriority maps our string NotificationPriority onto the
+// admin SDK's typed priority constants.
+func androidNotificationPriority(p string) messaging.AndroidNotificationPriority {
+	switch p {
+	case "PRIORITY_MIN":
+		return messaging.PriorityMin
+	case "PRIORITY_LOW":
+		return messaging.PriorityLow
+	case "PRIORITY_DEFAULT":
+		return messaging.PriorityDefault
+	case "PRIORITY_HIGH":
+		return messaging.PriorityHigh
+	case "PRIORITY_MAX":
+		return messaging.PriorityMax
+	default:
+		// Zero value of messaging.AndroidNotificationPriority, i.e.
+		// "priority unspecified".
+		return 0
+	}
+}
+
+// toAdminAPNSConfig translates our APNSConfig into the firebase-admin SDK's
+// equivalent, used by the sendByApp path. Aps.Alert may be a plain string,
+// which maps onto messaging.Aps.AlertString, or a richer object (e.g. a
+// title/body/loc-args dictionary), which is round-tripped through JSON into
+// a messaging.ApsAlert; an error is returned if it is neither.
+func toAdminAPNSConfig(a *APNSConfig) (*messaging.APNSConfig, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	cfg := &messaging.APNSConfig{
+		Headers: a.Headers,
+	}
+	if a.Payload != nil {
+		payload := &messaging.APNSPayload{
+			CustomData: a.Payload.CustomData,
+		}
+		if a.Payload.Aps != nil {
+			payload.Aps = &messaging.Aps{
+				Badge:            a.Payload.Aps.Badge,
+				Sound:            a.Payload.Aps.Sound,
+				ContentAvailable: a.Payload.Aps.ContentAvailable,
+				MutableContent:   a.Payload.Aps.MutableContent,
+				Category:         a.Payload.Aps.Category,
+				ThreadID:         a.Payload.Aps.ThreadID,
+			}
+			switch alert := a.Payload.Aps.Alert.(type) {
+			case nil:
+			case string:
+				payload.Aps.AlertString = alert
+			default:
+				b, err := json.Marshal(alert)
+				if err != nil {
+					return nil, fmt.Errorf("fcm: marshal aps alert: %w", err)
+				}
+				var apsAlert messaging.ApsAlert
+				if err := json.Unmarshal(b, &apsAlert); err != nil {
+					return nil, fmt.Errorf("fcm: aps alert must be a string or an object matching messaging.ApsAlert: %w", err)
+				}
+				payload.Aps.Alert = &apsAlert
+			}
+		}
+		cfg.Payload = payload
+	}
+	return cfg, nil
+}
+
+// toAdminWebpushConfig translates our WebpushConfig into the firebase-admin
+// SDK's equivalent, used by the sendByApp path.
+func toAdminWebpushConfig(w *WebpushConfig) *messaging.WebpushConfig {
+	if w == nil {
+		return nil
+	}
+
+	cfg := &messaging.WebpushConfig{
+		Headers: w.Headers,
+		Data:    w.Data,
+	}
+	if w.Notification != nil {
+		cfg.Notification = &messaging.WebpushNotification{CustomData: w.Notification}
+	}
+	if w.FCMOptions != nil {
+		cfg.FcmOptions = &messaging.WebpushFcmOptions{Link: w.FCMOptions.Link}
+	}
+	return cfg
+}
+
+// toAdminFCMOptions translates our FCMOptions into the firebase-admin SDK's
+// equivalent, used by the sendByApp path.
+func toAdminFCMOptions(o *FCMOptions) *messaging.FCMOptions {
+	if o == nil {
+		return nil
+	}
+	return &messaging.FCMOptions{AnalyticsLabel: o.AnalyticsLabel}
+}
@@ -0,0 +1,24 @@
+package fcm
+
+// Result is the outcome of delivering a message to a single registration
+// token. Error is set only on the firebase-admin path (NewClientWithCredentials)
+// and holds the SDK's error. A v1 send returns its typed error (e.g.
+// ErrUnregistered) from Send itself, and SendAll/SendMulticast report
+// per-token typed errors in SendResponse.Error.
+type Result struct {
+	Error             error  `json:"-"`
+	ErrorResponseCode string `json:"error,omitempty"`
+	MessageID         string `json:"message_id,omitempty"`
+	RegistrationID    string `json:"registration_id,omitempty"`
+}
+
+// Response is returned by Send, SendWithContext and SendWithRetryWithContext.
+type Response struct {
+	MulticastID int64    `json:"multicast_id,omitempty"`
+	Success     int      `json:"success,omitempty"`
+	Failure     int      `json:"failure,omitempty"`
+	Results     []Result `json:"results,omitempty"`
+
+	Error             error  `json:"-"`
+	ErrorResponseCode string `json:"-"`
+}
@@ -0,0 +1,77 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// isRetryable reports whether err represents a transient condition that is
+// safe to retry. Of the typed FCM v1 errors, only UNAVAILABLE, INTERNAL and
+// QUOTA_EXCEEDED are retried; INVALID_ARGUMENT, UNREGISTERED,
+// SENDER_ID_MISMATCH and THIRD_PARTY_AUTH_ERROR are permanent and
+// short-circuit immediately. Untyped errors (e.g. from the legacy endpoint
+// or a dropped connection) are treated as retryable, matching prior
+// behavior.
+func isRetryable(err error) bool {
+	switch err {
+	case ErrUnavailable, ErrInternal, ErrQuotaExceeded:
+		return true
+	case ErrInvalidArgument, ErrUnregistered, ErrSenderIDMismatch, ErrThirdPartyAuth:
+		return false
+	default:
+		return true
+	}
+}
+
+// retry calls fn, backing off between attempts per policy, until it
+// succeeds, returns a non-retryable error, exhausts maxAttempts (or
+// policy.MaxAttempts if smaller), exceeds policy.MaxElapsedTime, or ctx is
+// done. A Retry-After hint attached to fn's error via retryAfterError
+// overrides the computed delay for that attempt.
+func retry(ctx context.Context, policy BackoffPolicy, maxAttempts int, fn func() error) error {
+	if policy.MaxAttempts > 0 && policy.MaxAttempts < maxAttempts {
+		maxAttempts = policy.MaxAttempts
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	start := time.Now()
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		delay := policy.next(attempt)
+		var ra *retryAfterError
+		if errors.As(err, &ra) {
+			lastErr = ra.err
+			if ra.delay > 0 {
+				delay = ra.delay
+			}
+		} else {
+			lastErr = err
+		}
+
+		if !isRetryable(lastErr) {
+			return lastErr
+		}
+		if attempt == maxAttempts-1 {
+			break
+		}
+		if policy.MaxElapsedTime > 0 && time.Since(start)+delay > policy.MaxElapsedTime {
+			return lastErr
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return lastErr
+}
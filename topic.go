@@ -0,0 +1,123 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	iidSubscribeEndpoint   = "https://iid.googleapis.com/iid/v1:batchAdd"
+	iidUnsubscribeEndpoint = "https://iid.googleapis.com/iid/v1:batchRemove"
+)
+
+// TopicManagementError reports why a single registration token failed to
+// subscribe to or unsubscribe from a topic.
+type TopicManagementError struct {
+	Index  int
+	Reason string
+}
+
+// TopicManagementResponse is the outcome of SubscribeToTopic or
+// UnsubscribeFromTopic.
+type TopicManagementResponse struct {
+	SuccessCount int
+	FailureCount int
+	Errors       []TopicManagementError
+}
+
+// SubscribeToTopic subscribes the given registration tokens to topic.
+func (c *Client) SubscribeToTopic(ctx context.Context, tokens []string, topic string) (*TopicManagementResponse, error) {
+	return c.manageTopic(ctx, iidSubscribeEndpoint, tokens, topic)
+}
+
+// UnsubscribeFromTopic unsubscribes the given registration tokens from
+// topic.
+func (c *Client) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) (*TopicManagementResponse, error) {
+	return c.manageTopic(ctx, iidUnsubscribeEndpoint, tokens, topic)
+}
+
+// manageTopic performs a batchAdd/batchRemove call against the Instance ID
+// API, which backs topic subscription management.
+func (c *Client) manageTopic(ctx context.Context, endpoint string, tokens []string, topic string) (*TopicManagementResponse, error) {
+	if len(tokens) == 0 {
+		return nil, ErrInvalidTarget
+	}
+	if c.tokenSource == nil {
+		return nil, errors.New("fcm: topic management requires a v1-capable client; use NewClientV1")
+	}
+
+	tok, err := c.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(struct {
+		To                 string   `json:"to"`
+		RegistrationTokens []string `json:"registration_tokens"`
+	}{
+		To:                 topicName(topic),
+		RegistrationTokens: tokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, connectionError(err.Error())
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseV1Error(resp.StatusCode, respBody)
+	}
+
+	var parsed struct {
+		Results []struct {
+			Error string `json:"error,omitempty"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	out := &TopicManagementResponse{}
+	for i, r := range parsed.Results {
+		if r.Error == "" {
+			out.SuccessCount++
+			continue
+		}
+		out.FailureCount++
+		out.Errors = append(out.Errors, TopicManagementError{Index: i, Reason: r.Error})
+	}
+
+	return out, nil
+}
+
+// topicName normalizes topic into the "/topics/<name>" form the Instance ID
+// API expects.
+func topicName(topic string) string {
+	if strings.HasPrefix(topic, "/topics/") {
+		return topic
+	}
+	return fmt.Sprintf("/topics/%s", topic)
+}